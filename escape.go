@@ -0,0 +1,106 @@
+package goini
+
+import "strings"
+
+// unescapeValue decodes a value read from the file when SetEscape(true) is in
+// effect. A value surrounded by double quotes has the quotes stripped, and the
+// escape sequences \;, \#, \\, \n, \t and \" are decoded wherever they occur.
+// This lets values contain the comment characters or the key/value separator
+// without those bytes being mistaken for syntax.
+func unescapeValue(v string) string {
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		v = v[1 : len(v)-1]
+	}
+
+	if !strings.ContainsRune(v, '\\') {
+		return v
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c == '\\' && i+1 < len(v) {
+			switch v[i+1] {
+			case ';':
+				buf.WriteByte(';')
+				i++
+				continue
+			case '#':
+				buf.WriteByte('#')
+				i++
+				continue
+			case '\\':
+				buf.WriteByte('\\')
+				i++
+				continue
+			case 'n':
+				buf.WriteByte('\n')
+				i++
+				continue
+			case 't':
+				buf.WriteByte('\t')
+				i++
+				continue
+			case '"':
+				buf.WriteByte('"')
+				i++
+				continue
+			}
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+// escapeValue encodes a value for write when SetEscape(true) is in effect.
+// The value is quoted when it contains the key/value separator, leading or
+// trailing whitespace, or a comment character (';' or '#'); otherwise those
+// bytes are backslash-escaped in place so the unquoted value stays on one
+// syntactically unambiguous line.
+func escapeValue(v, kvSep string) string {
+	if needsQuoting(v, kvSep) {
+		return `"` + escapeBytes(v, true) + `"`
+	}
+	return escapeBytes(v, false)
+}
+
+func needsQuoting(v, kvSep string) bool {
+	if v == "" {
+		return false
+	}
+	if kvSep != "" && strings.Contains(v, kvSep) {
+		return true
+	}
+	if strings.ContainsAny(v[:1], " \t") || strings.ContainsAny(v[len(v)-1:], " \t") {
+		return true
+	}
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		// Unquoted, this would be indistinguishable from the quoting wrapper
+		// unescapeValue strips on read, corrupting a value like `"a"` to `a`.
+		return true
+	}
+	return strings.ContainsAny(v, ";#")
+}
+
+func escapeBytes(v string, quoted bool) string {
+	var buf strings.Builder
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c == '\\':
+			buf.WriteString(`\\`)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c == '\t':
+			buf.WriteString(`\t`)
+		case c == '"' && quoted:
+			buf.WriteString(`\"`)
+		case (c == ';' || c == '#') && !quoted:
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,64 @@
+package goini
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEscapeValueRoundTrip(t *testing.T) {
+	cases := []string{
+		"plain",
+		"has space",
+		" leading",
+		"trailing ",
+		"semi;colon",
+		"hash#mark",
+		"key=value",
+		`"a"`,
+		`""`,
+		`say "hi"`,
+		"back\\slash",
+		"new\nline",
+		"a\ttab",
+	}
+
+	for _, v := range cases {
+		escaped := escapeValue(v, "=")
+		got := unescapeValue(escaped)
+		if got != v {
+			t.Errorf("escapeValue(%q) = %q, unescapeValue of that = %q, want %q", v, escaped, got, v)
+		}
+	}
+}
+
+func TestNeedsQuotingLiteralQuotes(t *testing.T) {
+	if !needsQuoting(`"a"`, "=") {
+		t.Error(`needsQuoting("a") = false, want true (would be mistaken for the quoting wrapper)`)
+	}
+	if needsQuoting(`"`, "=") {
+		t.Error(`needsQuoting(") = true, want false (a single quote byte isn't a wrapper)`)
+	}
+	if needsQuoting("plain", "=") {
+		t.Error("needsQuoting(plain) = true, want false")
+	}
+}
+
+func TestParseEscapedQuotedValueSurvivesReparse(t *testing.T) {
+	ini := New()
+	ini.SetEscape(true)
+	ini.Set("key", `"a"`)
+
+	var buf bytes.Buffer
+	if err := ini.Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	reparsed := New()
+	reparsed.SetEscape(true)
+	if err := reparsed.Parse(buf.Bytes(), "\n", "="); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if v, _ := reparsed.Get("key"); v != `"a"` {
+		t.Fatalf(`key = %q after round trip, want "a"`, v)
+	}
+}
@@ -31,6 +31,13 @@ type INI struct {
 	parseSection bool
 	skipCommits  bool
 	trimQuotes   bool // Whether to trim quotation marks. default is false.
+	escape       bool // Whether to escape/unescape values. See SetEscape.
+
+	preserve        bool    // Whether ParseFilePreserve/WritePreserve are in play.
+	lines           []*line // ordered line-level AST used by the preserve path.
+	trailingNewline bool    // Whether the source parsed by ParseFilePreserve ended in "\n".
+
+	loadOpts LoadOptions // options applied by Load. See SetLoadOptions.
 }
 
 func New() *INI {
@@ -93,6 +100,13 @@ func (ini *INI) SetTrimQuotes(v bool) {
 	ini.trimQuotes = v
 }
 
+// SetEscape sets INI.escape, which controls whether values are unescaped on
+// parse and escaped (and quoted, where necessary) on write. See the comment
+// on unescapeValue for the supported escape sequences.
+func (ini *INI) SetEscape(v bool) {
+	ini.escape = v
+}
+
 // Get looks up a value for a key in the default section
 // and returns that value, along with a boolean result similar to a map lookup.
 func (ini *INI) Get(key string) (string, bool) {
@@ -236,6 +250,10 @@ func (ini *INI) SectionSet(section, key, value string) {
 		ini.sections[section] = kvmap
 	}
 	kvmap[key] = value
+
+	if ini.preserve {
+		ini.preserveSet(section, key, value)
+	}
 }
 
 // Delete deletes the key in given section.
@@ -244,6 +262,10 @@ func (ini *INI) Delete(section, key string) {
 	if ok {
 		delete(kvmap, key)
 	}
+
+	if ini.preserve {
+		ini.preserveDelete(section, key)
+	}
 }
 
 // Write tries to write the INI data into an output.
@@ -262,7 +284,7 @@ func (ini *INI) Write(w io.Writer) error {
 		if section == DefaultSection {
 			continue
 		}
-		_, err := buf.WriteString("[" + section + "]" + ini.lineSep)
+		_, err := buf.WriteString("[" + sectionHeader(section) + "]" + ini.lineSep)
 		if err != nil {
 			return err
 		}
@@ -287,6 +309,10 @@ func (ini *INI) write(kv Kvmap, buf *bufio.Writer) error {
 			return err
 		}
 
+		if ini.escape {
+			v = escapeValue(v, ini.kvSep)
+		}
+
 		_, err = buf.WriteString(v)
 		if err != nil {
 			return err
@@ -322,8 +348,13 @@ func (ini *INI) parseINI(data []byte, lineSep, kvSep string) error {
 			continue
 		}
 		if ini.parseSection && line[0] == '[' && line[size-1] == ']' {
-			// Parse INI-Section
-			section = string(line[1 : size-1])
+			// Parse INI-Section, including git-config-style `[section "sub"]` subsections
+			base, sub, hasSub := parseSectionHeader(string(line[1 : size-1]))
+			if hasSub {
+				section = subsectionKey(base, sub)
+			} else {
+				section = base
+			}
 			kvmap = make(Kvmap)
 			ini.sections[section] = kvmap
 			continue
@@ -338,6 +369,12 @@ func (ini *INI) parseINI(data []byte, lineSep, kvSep string) error {
 
 		k := bytes.TrimSpace(line[0:pos])
 		v := bytes.TrimSpace(line[pos+len(kvSep):])
+
+		if ini.escape {
+			kvmap[string(k)] = unescapeValue(string(v))
+			continue
+		}
+
 		if ini.trimQuotes {
 			v = bytes.Trim(v, "'\"")
 		}
@@ -0,0 +1,211 @@
+package goini
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// LoadOptions configures the parsing Load performs. The zero value matches
+// the strict behavior of Parse/ParseFile: '=' is the only key/value
+// separator, every non-comment, non-blank, non-section line must contain
+// it, and a section repeated within a single source is an error. The one
+// exception is IgnoreContinuation: a trailing unescaped '\' joins a line
+// with the next by default, since that's the behavior most real-world
+// layered configs rely on; set it to true to fall back to Parse's literal
+// handling of '\'.
+type LoadOptions struct {
+	CaseInsensitiveKeys    bool // lower-case keys on read
+	AllowDuplicateSections bool // merge a section re-opened within one source instead of erroring
+	AllowBooleanKeys       bool // a line with no separator is read as key=true
+	AllowColonSeparator    bool // accept ':' as a key/value separator in addition to '='
+	IgnoreContinuation     bool // treat a trailing '\' as a literal character instead of joining with the next line
+	IgnoreInlineComments   bool // strip a trailing ';' or '#' comment from a value
+}
+
+// SetLoadOptions sets the options Load uses to parse each source.
+func (ini *INI) SetLoadOptions(opts LoadOptions) {
+	ini.loadOpts = opts
+}
+
+// Load parses each source in order and merges the result into ini: for any
+// given section+key, a source parsed later overrides one parsed earlier.
+// Each source must be a filename (string), raw INI data ([]byte), or an
+// io.Reader. Load behaves as a layered version of ParseFile/Parse/ParseFrom
+// and is configured globally via SetLoadOptions.
+func (ini *INI) Load(sources ...interface{}) error {
+	ini.parseSection = true
+
+	if ini.sections == nil {
+		ini.sections = make(SectionMap)
+	}
+
+	for _, src := range sources {
+		data, err := loadSourceBytes(src)
+		if err != nil {
+			return err
+		}
+		if err := ini.loadOne(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func loadSourceBytes(src interface{}) ([]byte, error) {
+	switch s := src.(type) {
+	case string:
+		return ioutil.ReadFile(s)
+	case []byte:
+		return s, nil
+	case io.Reader:
+		return ioutil.ReadAll(s)
+	default:
+		return nil, fmt.Errorf("goini: Load: unsupported source type %T", src)
+	}
+}
+
+func (ini *INI) loadOne(data []byte) error {
+	opts := ini.loadOpts
+
+	section := DefaultSection
+	kvmap := ini.sections[section]
+	if kvmap == nil {
+		kvmap = make(Kvmap)
+		ini.sections[section] = kvmap
+	}
+	seen := map[string]bool{section: true}
+
+	rawLines := bytes.Split(data, []byte("\n"))
+	if !opts.IgnoreContinuation {
+		rawLines = joinContinuations(rawLines)
+	}
+
+	for _, raw := range rawLines {
+		line := bytes.TrimSpace(raw)
+		size := len(line)
+		if size == 0 {
+			continue
+		}
+		if line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' && line[size-1] == ']' {
+			base, sub, hasSub := parseSectionHeader(string(line[1 : size-1]))
+			if hasSub {
+				section = subsectionKey(base, sub)
+			} else {
+				section = base
+			}
+
+			if seen[section] && !opts.AllowDuplicateSections {
+				return fmt.Errorf("goini: Load: section %q is repeated in this source", section)
+			}
+			seen[section] = true
+
+			kvmap = ini.sections[section]
+			if kvmap == nil {
+				kvmap = make(Kvmap)
+				ini.sections[section] = kvmap
+			}
+			continue
+		}
+
+		sepIdx, sepLen := findSeparator(line, opts.AllowColonSeparator)
+
+		var key, value string
+		if sepIdx < 0 {
+			if !opts.AllowBooleanKeys {
+				return errors.New("goini: Load: " + string(line) + " is NOT a valid key/value pair")
+			}
+			key = string(line)
+			value = "true"
+		} else {
+			key = string(bytes.TrimSpace(line[:sepIdx]))
+			value = string(bytes.TrimSpace(line[sepIdx+sepLen:]))
+		}
+
+		if opts.IgnoreInlineComments {
+			value = stripInlineComment(value)
+		}
+		if opts.CaseInsensitiveKeys {
+			key = strings.ToLower(key)
+		}
+
+		kvmap[key] = value
+	}
+
+	return nil
+}
+
+// findSeparator locates the key/value separator in line, optionally
+// accepting ':' alongside the default '=' and preferring whichever occurs
+// first.
+func findSeparator(line []byte, allowColon bool) (idx, length int) {
+	eq := bytes.IndexByte(line, '=')
+	if !allowColon {
+		if eq < 0 {
+			return -1, 0
+		}
+		return eq, 1
+	}
+
+	co := bytes.IndexByte(line, ':')
+	switch {
+	case eq < 0 && co < 0:
+		return -1, 0
+	case eq < 0:
+		return co, 1
+	case co < 0:
+		return eq, 1
+	case eq < co:
+		return eq, 1
+	default:
+		return co, 1
+	}
+}
+
+// joinContinuations folds any line ending in an odd number of trailing '\'
+// characters into the line that follows it, stripping the final '\' and any
+// leading whitespace off the continued line. An even number of trailing '\'
+// is left alone: each pair is an escaped literal backslash, not a
+// continuation marker. Continuations chain, so "a=1\\\nb\\\nc\n" joins into
+// a single "a=1bc".
+func joinContinuations(lines [][]byte) [][]byte {
+	joined := make([][]byte, 0, len(lines))
+
+	for i := 0; i < len(lines); i++ {
+		cur := lines[i]
+		for hasContinuation(cur) && i+1 < len(lines) {
+			cur = append(bytes.TrimRight(cur[:len(cur)-1], " \t"), bytes.TrimLeft(lines[i+1], " \t")...)
+			i++
+		}
+		joined = append(joined, cur)
+	}
+
+	return joined
+}
+
+// hasContinuation reports whether line ends in an odd number of trailing '\'
+// characters, i.e. an unescaped continuation marker.
+func hasContinuation(line []byte) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+func stripInlineComment(value string) string {
+	for i := 0; i < len(value); i++ {
+		if value[i] == ';' || value[i] == '#' {
+			return strings.TrimSpace(value[:i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,45 @@
+package goini
+
+import "testing"
+
+func TestLoadContinuationJoinsByDefault(t *testing.T) {
+	ini := New()
+	if err := ini.Load([]byte("[s]\nkey=part1\\\npart2\n")); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if v, _ := ini.SectionGet("s", "key"); v != "part1part2" {
+		t.Errorf("key = %q, want part1part2", v)
+	}
+}
+
+func TestLoadContinuationChains(t *testing.T) {
+	ini := New()
+	if err := ini.Load([]byte("[s]\nkey=a\\\nb\\\nc\n")); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if v, _ := ini.SectionGet("s", "key"); v != "abc" {
+		t.Errorf("key = %q, want abc", v)
+	}
+}
+
+func TestLoadIgnoreContinuationTreatsBackslashLiterally(t *testing.T) {
+	ini := New()
+	ini.SetLoadOptions(LoadOptions{IgnoreContinuation: true})
+	err := ini.Load([]byte("[s]\nkey=part1\\\npart2\n"))
+	if err == nil {
+		t.Fatalf("expected an error parsing the orphaned continuation line, got none")
+	}
+}
+
+func TestLoadEscapedTrailingBackslashIsNotAContinuation(t *testing.T) {
+	ini := New()
+	if err := ini.Load([]byte("[s]\nkey=part1\\\\\nother=2\n")); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if v, _ := ini.SectionGet("s", "key"); v != "part1\\\\" {
+		t.Errorf("key = %q, want part1\\\\ (escaped backslash, not joined)", v)
+	}
+	if v, _ := ini.SectionGet("s", "other"); v != "2" {
+		t.Errorf("other = %q, want 2", v)
+	}
+}
@@ -0,0 +1,257 @@
+package goini
+
+// Conflict describes a (section, key) where a and b changed base in
+// incompatible ways. Key is empty when the conflict concerns an entire
+// section (one side deleted it, the other side modified a key inside it).
+type Conflict struct {
+	Section string
+	Key     string
+	BaseVal string
+	AVal    string
+	BVal    string
+}
+
+// keyChange is a key-level DiffResult, narrowed to what Merge3 needs to
+// decide how to fold one side's change into the merged result.
+type keyChange struct {
+	state   int
+	baseVal string
+	newVal  string // meaningful when state != DIFF_KEY_ONLY_IN_A
+}
+
+func (c keyChange) resolved() (value string, exists bool) {
+	if c.state == DIFF_KEY_ONLY_IN_A {
+		return "", false
+	}
+	return c.newVal, true
+}
+
+// indexDiff sorts a DiffINI(base, other) result into per-section whole-section
+// states and per-section-and-key states, so Merge3 can look a section or key
+// up by name instead of re-deriving what changed relative to base itself.
+func indexDiff(diffs []*DiffResult) (sectionState map[string]int, keyState map[string]map[string]keyChange) {
+	sectionState = make(map[string]int)
+	keyState = make(map[string]map[string]keyChange)
+
+	for _, d := range diffs {
+		if d.Key == "" {
+			sectionState[d.Section] = d.State
+			continue
+		}
+		m := keyState[d.Section]
+		if m == nil {
+			m = make(map[string]keyChange)
+			keyState[d.Section] = m
+		}
+		m[d.Key] = keyChange{state: d.State, baseVal: d.AVal, newVal: d.BVal}
+	}
+
+	return sectionState, keyState
+}
+
+// Merge3 performs a three-way merge of a and b against their common
+// ancestor base. It is built on the same per-(section,key) comparison
+// DiffINI makes: DiffINI(base, a) and DiffINI(base, b) classify every
+// change on each side, and Merge3 folds those two classifications
+// together rather than re-deriving its own notion of what changed. For
+// every (section, key) found in any of the three inputs, the result is
+// classified as unchanged, added on one side, deleted on one side,
+// modified on one side (that side's value is taken), or modified on both
+// sides (a conflict unless both sides agree on the new value). A section
+// deleted on one side is dropped from the result unless the other side
+// modified a key inside it, in which case the deletion conflicts with
+// that modification and the modified side's keys are kept.
+func Merge3(base, a, b *INI) (*INI, []*Conflict, error) {
+	result := New()
+	for section, kv := range base.sections {
+		copySection(result, section, kv)
+	}
+
+	sectionStateA, keyStateA := indexDiff(DiffINI(base, a))
+	sectionStateB, keyStateB := indexDiff(DiffINI(base, b))
+
+	var conflicts []*Conflict
+	resolved := make(map[string]bool)
+
+	for _, section := range unionSectionStateKeys(sectionStateA, sectionStateB) {
+		stateA, inA := sectionStateA[section]
+		stateB, inB := sectionStateB[section]
+
+		aDeleted := inA && stateA == DIFF_SECTION_ONLY_IN_A
+		bDeleted := inB && stateB == DIFF_SECTION_ONLY_IN_A
+		aAdded := inA && stateA == DIFF_SECTION_ONLY_IN_B
+		bAdded := inB && stateB == DIFF_SECTION_ONLY_IN_B
+
+		switch {
+		case aDeleted && bDeleted:
+			delete(result.sections, section)
+		case aDeleted:
+			if sectionModified(base.sections[section], b.sections[section]) {
+				conflicts = append(conflicts, &Conflict{Section: section})
+				delete(result.sections, section)
+				copySection(result, section, b.sections[section])
+			} else {
+				delete(result.sections, section)
+			}
+		case bDeleted:
+			if sectionModified(base.sections[section], a.sections[section]) {
+				conflicts = append(conflicts, &Conflict{Section: section})
+				delete(result.sections, section)
+				copySection(result, section, a.sections[section])
+			} else {
+				delete(result.sections, section)
+			}
+		case aAdded && bAdded:
+			mergeNewSection(result, section, a.sections[section], b.sections[section], &conflicts)
+		case aAdded:
+			copySection(result, section, a.sections[section])
+		case bAdded:
+			copySection(result, section, b.sections[section])
+		default:
+			continue // neither side touched the section as a whole
+		}
+
+		resolved[section] = true
+	}
+
+	for _, section := range unionKeyStateKeys(keyStateA, keyStateB) {
+		if resolved[section] {
+			continue // already handled above as a whole-section add/delete
+		}
+
+		for key, ca := range keyStateA[section] {
+			cb, inB := keyStateB[section][key]
+			if !inB {
+				applyKeyChange(result, section, key, ca)
+				continue
+			}
+			mergeKeyChange(result, section, key, ca, cb, &conflicts)
+		}
+		for key, cb := range keyStateB[section] {
+			if _, inA := keyStateA[section][key]; inA {
+				continue // already handled in the loop above
+			}
+			applyKeyChange(result, section, key, cb)
+		}
+	}
+
+	return result, conflicts, nil
+}
+
+func applyKeyChange(result *INI, section, key string, c keyChange) {
+	if v, ok := c.resolved(); ok {
+		result.SectionSet(section, key, v)
+	} else {
+		result.Delete(section, key)
+	}
+}
+
+func mergeKeyChange(result *INI, section, key string, ca, cb keyChange, conflicts *[]*Conflict) {
+	aVal, aHas := ca.resolved()
+	bVal, bHas := cb.resolved()
+
+	switch {
+	case !aHas && !bHas:
+		result.Delete(section, key) // deleted on both sides
+	case aHas && !bHas:
+		*conflicts = append(*conflicts, &Conflict{Section: section, Key: key, BaseVal: ca.baseVal, AVal: aVal})
+		result.SectionSet(section, key, aVal)
+	case !aHas && bHas:
+		*conflicts = append(*conflicts, &Conflict{Section: section, Key: key, BaseVal: cb.baseVal, BVal: bVal})
+		result.SectionSet(section, key, bVal)
+	case aVal == bVal:
+		result.SectionSet(section, key, aVal) // changed identically on both sides
+	default:
+		*conflicts = append(*conflicts, &Conflict{Section: section, Key: key, BaseVal: ca.baseVal, AVal: aVal, BVal: bVal})
+		result.SectionSet(section, key, aVal)
+	}
+}
+
+// mergeNewSection handles a section added independently on both sides
+// (absent from base), which DiffINI reports as a bare section-level
+// addition with no per-key detail on either side, so a and b's contents
+// are compared directly here.
+func mergeNewSection(result *INI, section string, aKV, bKV Kvmap, conflicts *[]*Conflict) {
+	for _, key := range unionKvmapKeys(aKV, bKV) {
+		aVal, aOK := aKV[key]
+		bVal, bOK := bKV[key]
+
+		switch {
+		case aOK && !bOK:
+			result.SectionSet(section, key, aVal)
+		case !aOK && bOK:
+			result.SectionSet(section, key, bVal)
+		case aVal == bVal:
+			result.SectionSet(section, key, aVal)
+		default:
+			*conflicts = append(*conflicts, &Conflict{Section: section, Key: key, AVal: aVal, BVal: bVal})
+			result.SectionSet(section, key, aVal)
+		}
+	}
+}
+
+func sectionModified(base, other Kvmap) bool {
+	if len(base) != len(other) {
+		return true
+	}
+	for k, v := range base {
+		if ov, ok := other[k]; !ok || ov != v {
+			return true
+		}
+	}
+	return false
+}
+
+func copySection(dst *INI, section string, kv Kvmap) {
+	for k, v := range kv {
+		dst.SectionSet(section, k, v)
+	}
+}
+
+func unionSectionStateKeys(a, b map[string]int) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for k := range a {
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func unionKeyStateKeys(a, b map[string]map[string]keyChange) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for k := range a {
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func unionKvmapKeys(a, b Kvmap) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for k := range a {
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,105 @@
+package goini
+
+import "testing"
+
+func mustParseForTest(t *testing.T, data string) *INI {
+	t.Helper()
+	ini := New()
+	ini.SetParseSection(true)
+	if err := ini.Parse([]byte(data), "\n", "="); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return ini
+}
+
+func TestMerge3NoConflict(t *testing.T) {
+	base := mustParseForTest(t, "[server]\nport=8080\ntimeout=30\n")
+	a := mustParseForTest(t, "[server]\nport=9090\ntimeout=30\n")
+	b := mustParseForTest(t, "[server]\nport=8080\ntimeout=60\n")
+
+	merged, conflicts, err := Merge3(base, a, b)
+	if err != nil {
+		t.Fatalf("Merge3: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts, got %+v", conflicts)
+	}
+	if v, _ := merged.SectionGet("server", "port"); v != "9090" {
+		t.Errorf("port = %q, want 9090 (only a changed it)", v)
+	}
+	if v, _ := merged.SectionGet("server", "timeout"); v != "60" {
+		t.Errorf("timeout = %q, want 60 (only b changed it)", v)
+	}
+}
+
+func TestMerge3Conflict(t *testing.T) {
+	base := mustParseForTest(t, "[server]\ntimeout=30\n")
+	a := mustParseForTest(t, "[server]\ntimeout=60\n")
+	b := mustParseForTest(t, "[server]\ntimeout=90\n")
+
+	_, conflicts, err := Merge3(base, a, b)
+	if err != nil {
+		t.Fatalf("Merge3: %v", err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %+v", conflicts)
+	}
+	c := conflicts[0]
+	if c.Section != "server" || c.Key != "timeout" || c.BaseVal != "30" || c.AVal != "60" || c.BVal != "90" {
+		t.Errorf("unexpected conflict: %+v", c)
+	}
+}
+
+func TestMerge3SectionDeletedVsModified(t *testing.T) {
+	base := mustParseForTest(t, "[legacy]\nk=v\n")
+	a := mustParseForTest(t, "") // deleted the whole section
+	b := mustParseForTest(t, "[legacy]\nk=changed\n")
+
+	merged, conflicts, err := Merge3(base, a, b)
+	if err != nil {
+		t.Fatalf("Merge3: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Section != "legacy" || conflicts[0].Key != "" {
+		t.Fatalf("expected a whole-section conflict, got %+v", conflicts)
+	}
+	if v, ok := merged.SectionGet("legacy", "k"); !ok || v != "changed" {
+		t.Errorf("expected b's modification to survive the conflict, got %q ok=%v", v, ok)
+	}
+}
+
+func TestMerge3SectionDeletedUnmodifiedOtherSide(t *testing.T) {
+	base := mustParseForTest(t, "[legacy]\nk=v\n")
+	a := mustParseForTest(t, "") // deleted
+	b := mustParseForTest(t, "[legacy]\nk=v\n") // untouched
+
+	merged, conflicts, err := Merge3(base, a, b)
+	if err != nil {
+		t.Fatalf("Merge3: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no conflict when the other side left the section untouched, got %+v", conflicts)
+	}
+	if _, ok := merged.GetKvmap("legacy"); ok {
+		t.Errorf("expected the deletion to win, but section survived")
+	}
+}
+
+func TestMerge3SectionAddedIndependently(t *testing.T) {
+	base := mustParseForTest(t, "")
+	a := mustParseForTest(t, "[new]\nk1=fromA\n")
+	b := mustParseForTest(t, "[new]\nk1=fromA\nk2=fromB\n")
+
+	merged, conflicts, err := Merge3(base, a, b)
+	if err != nil {
+		t.Fatalf("Merge3: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no conflict for an agreeing independently-added section, got %+v", conflicts)
+	}
+	if v, _ := merged.SectionGet("new", "k1"); v != "fromA" {
+		t.Errorf("k1 = %q, want fromA", v)
+	}
+	if v, _ := merged.SectionGet("new", "k2"); v != "fromB" {
+		t.Errorf("k2 = %q, want fromB", v)
+	}
+}
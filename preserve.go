@@ -0,0 +1,198 @@
+package goini
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+// lineKind identifies what a line in the preserve-mode AST represents.
+type lineKind int
+
+const (
+	lineBlank lineKind = iota
+	lineComment
+	lineSection
+	lineKV
+)
+
+// line is one entry in the ordered AST that backs ParseFilePreserve/WritePreserve.
+// Blank lines, comments and section headers carry their original bytes in raw so
+// they round-trip byte-for-byte; key/value lines are re-rendered from key/value so
+// that Set/SectionSet/Delete can update them in place.
+type line struct {
+	kind    lineKind
+	raw     []byte // original text, used for lineBlank/lineComment/lineSection
+	section string // enclosing section at this point in the file
+	key     string
+	value   string
+}
+
+// ParseFilePreserve reads the INI file named by filename the same way ParseFile
+// does, but additionally records the original line order, blank lines, comments
+// and section-header formatting. Subsequent calls to Set, SectionSet and Delete
+// update that record so that WritePreserve reproduces the file with only the
+// touched lines changed.
+func (ini *INI) ParseFilePreserve(filename string) error {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	ini.preserve = true
+	ini.parseSection = true
+	return ini.parsePreserve(contents)
+}
+
+func (ini *INI) parsePreserve(data []byte) error {
+	ini.lineSep = DefaultLineSeparator
+	ini.kvSep = DefaultKeyValueSeparator
+
+	if ini.sections == nil {
+		ini.sections = make(SectionMap)
+	}
+
+	section := DefaultSection
+	ini.sections[section] = make(Kvmap)
+
+	rawLines := bytes.Split(data, []byte("\n"))
+	ini.trailingNewline = len(data) > 0 && data[len(data)-1] == '\n'
+	for i, raw := range rawLines {
+		if i == len(rawLines)-1 && len(raw) == 0 {
+			// trailing newline produces a phantom empty element; drop it
+			continue
+		}
+
+		trimmed := bytes.TrimSpace(raw)
+		size := len(trimmed)
+		switch {
+		case size == 0:
+			ini.lines = append(ini.lines, &line{kind: lineBlank, section: section, raw: copyBytes(raw)})
+		case trimmed[0] == ';' || trimmed[0] == '#':
+			ini.lines = append(ini.lines, &line{kind: lineComment, section: section, raw: copyBytes(raw)})
+		case trimmed[0] == '[' && trimmed[size-1] == ']':
+			base, sub, hasSub := parseSectionHeader(string(trimmed[1 : size-1]))
+			if hasSub {
+				section = subsectionKey(base, sub)
+			} else {
+				section = base
+			}
+			if _, ok := ini.sections[section]; !ok {
+				ini.sections[section] = make(Kvmap)
+			}
+			ini.lines = append(ini.lines, &line{kind: lineSection, section: section, raw: copyBytes(raw)})
+		default:
+			pos := bytes.Index(trimmed, []byte(ini.kvSep))
+			if pos < 0 {
+				return errors.New("Came accross an error : " + string(trimmed) + " is NOT a valid key/value pair")
+			}
+			k := string(bytes.TrimSpace(trimmed[0:pos]))
+			v := string(bytes.TrimSpace(trimmed[pos+len(ini.kvSep):]))
+			ini.sections[section][k] = v
+			ini.lines = append(ini.lines, &line{kind: lineKV, section: section, key: k, value: v})
+		}
+	}
+	return nil
+}
+
+// WritePreserve writes the INI back out using the ordered AST recorded by
+// ParseFilePreserve, so blank lines, comments and section-header formatting are
+// reproduced exactly and only lines touched by Set/SectionSet/Delete differ from
+// the original file.
+func (ini *INI) WritePreserve(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+
+	for i, l := range ini.lines {
+		switch l.kind {
+		case lineBlank, lineComment, lineSection:
+			if _, err := buf.Write(l.raw); err != nil {
+				return err
+			}
+		case lineKV:
+			if _, err := buf.WriteString(l.key); err != nil {
+				return err
+			}
+			if _, err := buf.WriteString(ini.kvSep); err != nil {
+				return err
+			}
+			if _, err := buf.WriteString(l.value); err != nil {
+				return err
+			}
+		}
+
+		if i < len(ini.lines)-1 || ini.trailingNewline {
+			if _, err := buf.WriteString("\n"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return buf.Flush()
+}
+
+// preserveSet updates the line AST to match a SectionSet call: every
+// existing line for the key is edited in place (a section reopened later
+// in the file, e.g. `[foo]...[bar]...[foo]...`, can produce more than
+// one), otherwise a new line is inserted at the end of the key's section
+// (or a new section is appended when the section doesn't exist yet).
+func (ini *INI) preserveSet(section, key, value string) {
+	found := false
+	for _, l := range ini.lines {
+		if l.kind == lineKV && l.section == section && l.key == key {
+			l.value = value
+			found = true
+		}
+	}
+	if found {
+		return
+	}
+
+	newKV := &line{kind: lineKV, section: section, key: key, value: value}
+
+	lastIdx := -1
+	for i, l := range ini.lines {
+		if l.section == section {
+			lastIdx = i
+		}
+	}
+
+	if lastIdx >= 0 {
+		ini.insertLine(lastIdx+1, newKV)
+		return
+	}
+
+	if section == DefaultSection {
+		ini.insertLine(0, newKV)
+		return
+	}
+
+	ini.lines = append(ini.lines, &line{kind: lineSection, section: section, raw: []byte("[" + sectionHeader(section) + "]")}, newKV)
+}
+
+// preserveDelete removes every line for a key from the AST (a section
+// reopened later in the file can produce more than one), so a deleted key
+// doesn't reappear on a subsequent parse of the written-out file.
+func (ini *INI) preserveDelete(section, key string) {
+	kept := ini.lines[:0]
+	for _, l := range ini.lines {
+		if l.kind == lineKV && l.section == section && l.key == key {
+			continue
+		}
+		kept = append(kept, l)
+	}
+	ini.lines = kept
+}
+
+func (ini *INI) insertLine(at int, l *line) {
+	ini.lines = append(ini.lines, nil)
+	copy(ini.lines[at+1:], ini.lines[at:])
+	ini.lines[at] = l
+}
+
+func copyBytes(b []byte) []byte {
+	out := make([]byte, len(b))
+	copy(out, b)
+	return out
+}
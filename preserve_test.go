@@ -0,0 +1,96 @@
+package goini
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPreserveSetReopenedSection(t *testing.T) {
+	ini := New()
+	ini.preserve = true
+	ini.parseSection = true
+	data := "[foo]\nkey=first\n[bar]\nb=2\n[foo]\nkey=second\n"
+	if err := ini.parsePreserve([]byte(data)); err != nil {
+		t.Fatalf("parsePreserve: %v", err)
+	}
+
+	ini.SectionSet("foo", "key", "EDITED")
+
+	var out bytes.Buffer
+	if err := ini.WritePreserve(&out); err != nil {
+		t.Fatalf("WritePreserve: %v", err)
+	}
+
+	reparsed := New()
+	reparsed.SetParseSection(true)
+	if err := reparsed.Parse(out.Bytes(), "\n", "="); err != nil {
+		t.Fatalf("Parse of written output: %v", err)
+	}
+	if v, _ := reparsed.SectionGet("foo", "key"); v != "EDITED" {
+		t.Fatalf("foo.key = %q after round trip, want EDITED\noutput was:\n%s", v, out.String())
+	}
+}
+
+func TestPreserveDeleteReopenedSection(t *testing.T) {
+	ini := New()
+	ini.preserve = true
+	ini.parseSection = true
+	data := "[foo]\nkey=first\n[bar]\nb=2\n[foo]\nkey=second\n"
+	if err := ini.parsePreserve([]byte(data)); err != nil {
+		t.Fatalf("parsePreserve: %v", err)
+	}
+
+	ini.Delete("foo", "key")
+
+	var out bytes.Buffer
+	if err := ini.WritePreserve(&out); err != nil {
+		t.Fatalf("WritePreserve: %v", err)
+	}
+
+	reparsed := New()
+	reparsed.SetParseSection(true)
+	if err := reparsed.Parse(out.Bytes(), "\n", "="); err != nil {
+		t.Fatalf("Parse of written output: %v", err)
+	}
+	if _, ok := reparsed.SectionGet("foo", "key"); ok {
+		t.Fatalf("foo.key survived the delete after round trip\noutput was:\n%s", out.String())
+	}
+}
+
+func TestWritePreserveNoTrailingNewlineUntouched(t *testing.T) {
+	ini := New()
+	ini.preserve = true
+	ini.parseSection = true
+	data := "[foo]\nkey=value"
+	if err := ini.parsePreserve([]byte(data)); err != nil {
+		t.Fatalf("parsePreserve: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := ini.WritePreserve(&out); err != nil {
+		t.Fatalf("WritePreserve: %v", err)
+	}
+
+	if out.String() != data {
+		t.Fatalf("WritePreserve of an untouched read = %q, want %q (no spurious trailing newline)", out.String(), data)
+	}
+}
+
+func TestWritePreserveTrailingNewlinePreserved(t *testing.T) {
+	ini := New()
+	ini.preserve = true
+	ini.parseSection = true
+	data := "[foo]\nkey=value\n"
+	if err := ini.parsePreserve([]byte(data)); err != nil {
+		t.Fatalf("parsePreserve: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := ini.WritePreserve(&out); err != nil {
+		t.Fatalf("WritePreserve: %v", err)
+	}
+
+	if out.String() != data {
+		t.Fatalf("WritePreserve of an untouched read = %q, want %q", out.String(), data)
+	}
+}
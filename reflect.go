@@ -0,0 +1,309 @@
+package goini
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// fieldTag is the parsed form of an `ini:"..."` struct tag.
+//
+//	ini:"key,section=foo,omitempty,layout=2006-01-02,delim=|"
+//
+// subsection only applies to a nested-struct field: it names the parent
+// section, with the field's name (tag.name) used as the subsection name, so
+// the nested struct maps to the `section\x1fsub` key subsection.go uses
+// rather than a flat top-level section.
+type fieldTag struct {
+	name       string
+	section    string
+	subsection string
+	omitempty  bool
+	layout     string
+	delim      string
+	skip       bool
+}
+
+func parseFieldTag(field reflect.StructField) fieldTag {
+	tag := fieldTag{name: field.Name, delim: ","}
+
+	raw, ok := field.Tag.Lookup("ini")
+	if !ok {
+		return tag
+	}
+
+	parts := strings.Split(raw, ",")
+	if parts[0] == "-" {
+		tag.skip = true
+		return tag
+	}
+	if parts[0] != "" {
+		tag.name = parts[0]
+	}
+
+	for _, opt := range parts[1:] {
+		switch {
+		case opt == "omitempty":
+			tag.omitempty = true
+		case strings.HasPrefix(opt, "section="):
+			tag.section = strings.TrimPrefix(opt, "section=")
+		case strings.HasPrefix(opt, "subsection="):
+			tag.subsection = strings.TrimPrefix(opt, "subsection=")
+		case strings.HasPrefix(opt, "layout="):
+			tag.layout = strings.TrimPrefix(opt, "layout=")
+		case strings.HasPrefix(opt, "delim="):
+			tag.delim = strings.TrimPrefix(opt, "delim=")
+		}
+	}
+
+	return tag
+}
+
+// MapTo unmarshals the INI data into v, which must be a pointer to a struct.
+// Fields are matched using the `ini:"key,section=foo,omitempty"` tag
+// described on fieldTag; a field without a tag is matched by its Go field
+// name in the default section. A struct-typed field (other than
+// time.Time) is treated as its own section, named after the field's tag
+// name or field name; tagging it `subsection=parent` instead maps it to the
+// `[parent "name"]` subsection of parent.
+func (ini *INI) MapTo(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("goini: MapTo requires a non-nil pointer to a struct")
+	}
+
+	return ini.mapStructTo(rv.Elem(), DefaultSection)
+}
+
+func (ini *INI) mapStructTo(sv reflect.Value, section string) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		tag := parseFieldTag(field)
+		if tag.skip {
+			continue
+		}
+
+		fv := sv.Field(i)
+
+		if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+			if err := ini.mapStructTo(fv, nestedSection(tag)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		effSection := section
+		if tag.section != "" {
+			effSection = tag.section
+		}
+
+		value, ok := ini.SectionGet(effSection, tag.name)
+		if !ok {
+			continue
+		}
+
+		if err := setFieldValue(fv, value, tag); err != nil {
+			return fmt.Errorf("goini: field %s: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// nestedSection computes the section a nested-struct field maps to: tag.name
+// by default (or tag.section when set), or the `section\x1fsub` subsection
+// key when tag.subsection names a parent section.
+func nestedSection(tag fieldTag) string {
+	section := tag.name
+	if tag.section != "" {
+		section = tag.section
+	}
+	if tag.subsection != "" {
+		return subsectionKey(tag.subsection, tag.name)
+	}
+	return section
+}
+
+func setFieldValue(fv reflect.Value, value string, tag fieldTag) error {
+	switch {
+	case fv.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	case fv.Type() == timeType:
+		layout := tag.layout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Slice:
+		return setSliceValue(fv, value, tag)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+
+	return nil
+}
+
+func setSliceValue(fv reflect.Value, value string, tag fieldTag) error {
+	if value == "" {
+		fv.Set(reflect.MakeSlice(fv.Type(), 0, 0))
+		return nil
+	}
+
+	parts := strings.Split(value, tag.delim)
+	out := reflect.MakeSlice(fv.Type(), len(parts), len(parts))
+	for i, p := range parts {
+		if err := setFieldValue(out.Index(i), strings.TrimSpace(p), tag); err != nil {
+			return err
+		}
+	}
+	fv.Set(out)
+	return nil
+}
+
+// ReflectFrom marshals v, which must be a pointer to a struct or a struct,
+// into ini using the same `ini:"..."` tag rules as MapTo, including
+// `subsection=parent` on nested-struct fields.
+func (ini *INI) ReflectFrom(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return errors.New("goini: ReflectFrom requires a non-nil struct or pointer to struct")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return errors.New("goini: ReflectFrom requires a struct or pointer to struct")
+	}
+
+	return ini.reflectStructFrom(rv, DefaultSection)
+}
+
+func (ini *INI) reflectStructFrom(sv reflect.Value, section string) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		tag := parseFieldTag(field)
+		if tag.skip {
+			continue
+		}
+
+		fv := sv.Field(i)
+
+		if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+			if err := ini.reflectStructFrom(fv, nestedSection(tag)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if tag.omitempty && fv.IsZero() {
+			continue
+		}
+
+		effSection := section
+		if tag.section != "" {
+			effSection = tag.section
+		}
+
+		value, err := fieldValueToString(fv, tag)
+		if err != nil {
+			return fmt.Errorf("goini: field %s: %w", field.Name, err)
+		}
+
+		ini.SectionSet(effSection, tag.name, value)
+	}
+
+	return nil
+}
+
+func fieldValueToString(fv reflect.Value, tag fieldTag) (string, error) {
+	switch {
+	case fv.Type() == durationType:
+		return time.Duration(fv.Int()).String(), nil
+	case fv.Type() == timeType:
+		layout := tag.layout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return fv.Interface().(time.Time).Format(layout), nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), nil
+	case reflect.Slice:
+		parts := make([]string, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			s, err := fieldValueToString(fv.Index(i), tag)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = s
+		}
+		return strings.Join(parts, tag.delim), nil
+	default:
+		return "", fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+}
@@ -0,0 +1,85 @@
+package goini
+
+import "testing"
+
+type remoteCfg struct {
+	URL string `ini:"url"`
+}
+
+type repoCfg struct {
+	Origin remoteCfg `ini:"origin,subsection=remote"`
+}
+
+func TestReflectFromNestedSubsection(t *testing.T) {
+	ini := New()
+	cfg := repoCfg{Origin: remoteCfg{URL: "git@example.com:foo.git"}}
+	if err := ini.ReflectFrom(&cfg); err != nil {
+		t.Fatalf("ReflectFrom: %v", err)
+	}
+
+	v, ok := ini.SubsectionGet("remote", "origin", "url")
+	if !ok || v != "git@example.com:foo.git" {
+		t.Fatalf("remote.origin.url = %q ok=%v, want git@example.com:foo.git", v, ok)
+	}
+}
+
+func TestMapToNestedSubsection(t *testing.T) {
+	ini := New()
+	ini.SubsectionSet("remote", "origin", "url", "git@example.com:foo.git")
+
+	var cfg repoCfg
+	if err := ini.MapTo(&cfg); err != nil {
+		t.Fatalf("MapTo: %v", err)
+	}
+
+	if cfg.Origin.URL != "git@example.com:foo.git" {
+		t.Errorf("cfg.Origin.URL = %q, want git@example.com:foo.git", cfg.Origin.URL)
+	}
+}
+
+type plainNestCfg struct {
+	Server struct {
+		Port string `ini:"port"`
+	} `ini:"server"`
+}
+
+func TestReflectFromNestedPlainSectionStillWorks(t *testing.T) {
+	ini := New()
+	var cfg plainNestCfg
+	cfg.Server.Port = "8080"
+	if err := ini.ReflectFrom(&cfg); err != nil {
+		t.Fatalf("ReflectFrom: %v", err)
+	}
+
+	v, ok := ini.SectionGet("server", "port")
+	if !ok || v != "8080" {
+		t.Fatalf("server.port = %q ok=%v, want 8080", v, ok)
+	}
+}
+
+type narrowIntCfg struct {
+	Code int8 `ini:"code"`
+}
+
+func TestMapToRejectsOutOfRangeNarrowInt(t *testing.T) {
+	ini := New()
+	ini.Set("code", "200") // overflows int8 (max 127)
+
+	var cfg narrowIntCfg
+	if err := ini.MapTo(&cfg); err == nil {
+		t.Fatalf("MapTo silently wrapped 200 into int8 as %d, want an error", cfg.Code)
+	}
+}
+
+func TestMapToAcceptsInRangeNarrowInt(t *testing.T) {
+	ini := New()
+	ini.Set("code", "100")
+
+	var cfg narrowIntCfg
+	if err := ini.MapTo(&cfg); err != nil {
+		t.Fatalf("MapTo: %v", err)
+	}
+	if cfg.Code != 100 {
+		t.Errorf("cfg.Code = %d, want 100", cfg.Code)
+	}
+}
@@ -0,0 +1,165 @@
+package goini
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// streamConfig holds the options ParseStream and Walk apply while scanning.
+type streamConfig struct {
+	kvSep        string
+	parseSection bool
+	skipComments bool
+	trimQuotes   bool
+	escape       bool
+	maxLineSize  int
+}
+
+func defaultStreamConfig() streamConfig {
+	return streamConfig{
+		kvSep:        DefaultKeyValueSeparator,
+		parseSection: true,
+		skipComments: true,
+	}
+}
+
+// Option configures ParseStream and Walk.
+type Option func(*streamConfig)
+
+// WithKVSeparator sets the key/value separator used while scanning. Default: "=".
+func WithKVSeparator(sep string) Option {
+	return func(c *streamConfig) { c.kvSep = sep }
+}
+
+// WithParseSection sets whether `[section]` headers are recognized. Default: true.
+func WithParseSection(v bool) Option {
+	return func(c *streamConfig) { c.parseSection = v }
+}
+
+// WithSkipComments sets whether lines starting with ';' or '#' are skipped. Default: true.
+func WithSkipComments(v bool) Option {
+	return func(c *streamConfig) { c.skipComments = v }
+}
+
+// WithTrimQuotes sets whether surrounding quotation marks are trimmed from values. Default: false.
+func WithTrimQuotes(v bool) Option {
+	return func(c *streamConfig) { c.trimQuotes = v }
+}
+
+// WithEscape sets whether values are unescaped as described on unescapeValue. Default: false.
+func WithEscape(v bool) Option {
+	return func(c *streamConfig) { c.escape = v }
+}
+
+// WithMaxLineSize sets the largest line bufio.Scanner will accept, for
+// documents with lines longer than the default 64KiB token limit.
+func WithMaxLineSize(n int) Option {
+	return func(c *streamConfig) { c.maxLineSize = n }
+}
+
+// ParseStream parses r with a bufio.Scanner instead of reading the whole
+// document into memory, so multi-megabyte config files or piped stdin don't
+// require an ioutil.ReadAll/bytes.Split up front. It stores the result in
+// ini the same way Parse does; use Walk instead if you don't want the
+// parsed data materialized into a SectionMap at all.
+func (ini *INI) ParseStream(r io.Reader, opts ...Option) error {
+	cfg := defaultStreamConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if ini.sections == nil {
+		ini.sections = make(SectionMap)
+	}
+
+	ensureSection := func(section string) {
+		if ini.sections[section] == nil {
+			ini.sections[section] = make(Kvmap)
+		}
+	}
+	ensureSection(DefaultSection)
+
+	return walkStream(r, cfg, ensureSection, func(section, key, value string) error {
+		ensureSection(section)
+		ini.sections[section][key] = value
+		return nil
+	})
+}
+
+// Walk parses r the same way ParseStream does, but instead of building a
+// SectionMap it invokes fn with each section/key/value as it is parsed.
+// This lets callers filter or transform a large or streamed document
+// without ever materializing the full result in memory. Returning an error
+// from fn stops the walk and is returned from Walk.
+func Walk(r io.Reader, fn func(section, key, value string) error, opts ...Option) error {
+	cfg := defaultStreamConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return walkStream(r, cfg, nil, fn)
+}
+
+// walkStream scans r line by line, invoking onSection (if non-nil) as each
+// `[section]` header is parsed and fn for each key/value pair.
+func walkStream(r io.Reader, cfg streamConfig, onSection func(section string), fn func(section, key, value string) error) error {
+	kvSep := cfg.kvSep
+	if kvSep == "" {
+		kvSep = DefaultKeyValueSeparator
+	}
+	kvSepBytes := []byte(kvSep)
+
+	scanner := bufio.NewScanner(r)
+	if cfg.maxLineSize > 0 {
+		bufSize := 64 * 1024
+		if cfg.maxLineSize < bufSize {
+			bufSize = cfg.maxLineSize
+		}
+		scanner.Buffer(make([]byte, 0, bufSize), cfg.maxLineSize)
+	}
+
+	section := DefaultSection
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		size := len(line)
+		if size == 0 {
+			continue
+		}
+		if cfg.skipComments && (line[0] == ';' || line[0] == '#') {
+			continue
+		}
+		if cfg.parseSection && line[0] == '[' && line[size-1] == ']' {
+			base, sub, hasSub := parseSectionHeader(string(line[1 : size-1]))
+			if hasSub {
+				section = subsectionKey(base, sub)
+			} else {
+				section = base
+			}
+			if onSection != nil {
+				onSection(section)
+			}
+			continue
+		}
+
+		pos := bytes.Index(line, kvSepBytes)
+		if pos < 0 {
+			return errors.New("goini: " + string(line) + " is NOT a valid key/value pair")
+		}
+
+		k := string(bytes.TrimSpace(line[:pos]))
+		v := string(bytes.TrimSpace(line[pos+len(kvSepBytes):]))
+		if cfg.escape {
+			v = unescapeValue(v)
+		} else if cfg.trimQuotes {
+			v = string(bytes.Trim([]byte(v), `'"`))
+		}
+
+		if err := fn(section, k, v); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,91 @@
+package goini
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStreamRegistersEmptySections(t *testing.T) {
+	ini := New()
+	data := "[empty]\n[full]\nkey=value\n"
+	if err := ini.ParseStream(strings.NewReader(data)); err != nil {
+		t.Fatalf("ParseStream: %v", err)
+	}
+
+	if _, ok := ini.GetKvmap("empty"); !ok {
+		t.Error("empty section was not registered by ParseStream")
+	}
+	if v, ok := ini.SectionGet("full", "key"); !ok || v != "value" {
+		t.Errorf("full.key = %q, %v, want value, true", v, ok)
+	}
+}
+
+func TestParseStreamBasicKeyValues(t *testing.T) {
+	ini := New()
+	data := "[s]\na=1\nb=2\n"
+	if err := ini.ParseStream(strings.NewReader(data)); err != nil {
+		t.Fatalf("ParseStream: %v", err)
+	}
+	if v, _ := ini.SectionGet("s", "a"); v != "1" {
+		t.Errorf("s.a = %q, want 1", v)
+	}
+	if v, _ := ini.SectionGet("s", "b"); v != "2" {
+		t.Errorf("s.b = %q, want 2", v)
+	}
+}
+
+func TestWalkVisitsOnlyKeyValuePairs(t *testing.T) {
+	var kvs [][2]string
+	data := "[empty]\n[full]\nkey=value\n"
+
+	err := Walk(strings.NewReader(data), func(section, key, value string) error {
+		kvs = append(kvs, [2]string{section, key + "=" + value})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+	if len(kvs) != 1 || kvs[0][0] != "full" || kvs[0][1] != "key=value" {
+		t.Fatalf("Walk fn calls = %v, want exactly one call for full.key=value", kvs)
+	}
+}
+
+func TestWithMaxLineSizeAcceptsLinesWithinLimit(t *testing.T) {
+	ini := New()
+	data := "[s]\nkey=" + strings.Repeat("x", 100) + "\n"
+	if err := ini.ParseStream(strings.NewReader(data), WithMaxLineSize(1024)); err != nil {
+		t.Fatalf("ParseStream: %v", err)
+	}
+	if v, _ := ini.SectionGet("s", "key"); len(v) != 100 {
+		t.Errorf("key has length %d, want 100", len(v))
+	}
+}
+
+func TestWithMaxLineSizeRejectsOversizedLines(t *testing.T) {
+	ini := New()
+	data := "[s]\nkey=" + strings.Repeat("x", 1000) + "\n"
+	err := ini.ParseStream(strings.NewReader(data), WithMaxLineSize(64))
+	if err == nil {
+		t.Fatal("expected an error for a line longer than WithMaxLineSize, got none")
+	}
+}
+
+func TestWithMaxLineSizeSmallerThanDefaultIsHonored(t *testing.T) {
+	// Regression test: the initial buffer used to always be 64KiB regardless
+	// of maxLineSize, so a limit smaller than that was silently ignored
+	// because bufio.Scanner.Buffer uses the larger of the two.
+	ini := New()
+	data := "[s]\nkey=" + strings.Repeat("x", 200) + "\n"
+	err := ini.ParseStream(strings.NewReader(data), WithMaxLineSize(100))
+	if err == nil {
+		t.Fatal("expected WithMaxLineSize(100) to reject a ~200 byte line, got no error")
+	}
+}
+
+func TestParseStreamInvalidLineErrors(t *testing.T) {
+	ini := New()
+	err := ini.ParseStream(strings.NewReader("[s]\nnotakeyvalue\n"))
+	if err == nil {
+		t.Fatal("expected an error for a line with no key/value separator")
+	}
+}
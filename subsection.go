@@ -0,0 +1,108 @@
+package goini
+
+import (
+	"sort"
+	"strings"
+)
+
+// subsectionSep joins a section name and a subsection name into the single
+// string used as the key into SectionMap. \x1f (ASCII unit separator) can't
+// appear in a bracketed section header, so it can't collide with a real
+// section name.
+const subsectionSep = "\x1f"
+
+func subsectionKey(section, sub string) string {
+	return section + subsectionSep + sub
+}
+
+func splitSubsectionKey(key string) (section, sub string, ok bool) {
+	idx := strings.Index(key, subsectionSep)
+	if idx < 0 {
+		return key, "", false
+	}
+	return key[:idx], key[idx+len(subsectionSep):], true
+}
+
+// parseSectionHeader splits the bracketed contents of a section header into
+// a base section name and, for git-config-style headers like
+// `remote "origin"`, a subsection name. The split happens on the first
+// unescaped quote; \" and \\ are honored inside the subsection name.
+func parseSectionHeader(content string) (section, sub string, hasSub bool) {
+	qi := strings.IndexByte(content, '"')
+	if qi < 0 || qi == len(content)-1 || content[len(content)-1] != '"' {
+		return strings.TrimSpace(content), "", false
+	}
+
+	section = strings.TrimSpace(content[:qi])
+	sub = unescapeSubsectionName(content[qi+1 : len(content)-1])
+	return section, sub, true
+}
+
+// sectionHeader renders the bracketed header contents for a SectionMap key,
+// reconstituting the `name "sub"` form for subsections.
+func sectionHeader(key string) string {
+	if section, sub, ok := splitSubsectionKey(key); ok {
+		return section + ` "` + escapeSubsectionName(sub) + `"`
+	}
+	return key
+}
+
+func unescapeSubsectionName(s string) string {
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+			buf.WriteByte(s[i+1])
+			i++
+			continue
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+func escapeSubsectionName(s string) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '"' || c == '\\' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+// SubsectionGet looks up a value for a key in a named subsection of section,
+// e.g. SubsectionGet("remote", "origin", "url") for a `[remote "origin"]`
+// header, and returns that value along with a boolean result similar to a
+// map lookup. Subsection names are case-sensitive.
+func (ini *INI) SubsectionGet(section, sub, key string) (value string, ok bool) {
+	return ini.SectionGet(subsectionKey(section, sub), key)
+}
+
+// SubsectionSet stores the section/sub/key/value quadruple to this INI,
+// creating the subsection if it wasn't already present.
+func (ini *INI) SubsectionSet(section, sub, key, value string) {
+	ini.SectionSet(subsectionKey(section, sub), key, value)
+}
+
+// Subsections returns the names of the subsections defined under section,
+// sorted lexically.
+func (ini *INI) Subsections(section string) []string {
+	prefix := section + subsectionSep
+
+	var subs []string
+	for key := range ini.sections {
+		if sub, ok := strings.CutPrefix(key, prefix); ok {
+			subs = append(subs, sub)
+		}
+	}
+
+	sort.Strings(subs)
+	return subs
+}
@@ -0,0 +1,120 @@
+package goini
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParseSectionHeaderPlainSection(t *testing.T) {
+	section, sub, hasSub := parseSectionHeader("foo")
+	if section != "foo" || sub != "" || hasSub {
+		t.Fatalf("parseSectionHeader(foo) = (%q, %q, %v), want (foo, \"\", false)", section, sub, hasSub)
+	}
+}
+
+func TestParseSectionHeaderSubsection(t *testing.T) {
+	section, sub, hasSub := parseSectionHeader(`remote "origin"`)
+	if section != "remote" || sub != "origin" || !hasSub {
+		t.Fatalf(`parseSectionHeader(remote "origin") = (%q, %q, %v), want (remote, origin, true)`, section, sub, hasSub)
+	}
+}
+
+func TestParseSectionHeaderEscapedQuoteAndBackslash(t *testing.T) {
+	section, sub, hasSub := parseSectionHeader(`remote "a\"b\\c"`)
+	if section != "remote" || sub != `a"b\c` || !hasSub {
+		t.Fatalf(`parseSectionHeader(remote "a\"b\\c") = (%q, %q, %v), want (remote, a"b\c, true)`, section, sub, hasSub)
+	}
+}
+
+func TestParseSectionHeaderMalformedNotTreatedAsSubsection(t *testing.T) {
+	cases := []string{
+		`remote origin"`, // no opening quote
+		`remote "origin`, // no closing quote
+		`"just a quote`,  // unterminated
+	}
+	for _, content := range cases {
+		section, _, hasSub := parseSectionHeader(content)
+		if hasSub {
+			t.Errorf("parseSectionHeader(%q) reported hasSub=true for a malformed header", content)
+		}
+		if section == "" {
+			t.Errorf("parseSectionHeader(%q) returned an empty section", content)
+		}
+	}
+}
+
+func TestSectionHeaderRenderRoundTrip(t *testing.T) {
+	key := subsectionKey("remote", `a"b\c`)
+	rendered := sectionHeader(key)
+	if rendered != `remote "a\"b\\c"` {
+		t.Fatalf(`sectionHeader = %q, want remote "a\"b\\c"`, rendered)
+	}
+
+	section, sub, hasSub := parseSectionHeader(rendered)
+	if !hasSub || section != "remote" || sub != `a"b\c` {
+		t.Fatalf("round trip through parseSectionHeader = (%q, %q, %v), want (remote, a\"b\\c, true)", section, sub, hasSub)
+	}
+}
+
+func TestSectionHeaderPlainSectionUnchanged(t *testing.T) {
+	if got := sectionHeader("foo"); got != "foo" {
+		t.Fatalf("sectionHeader(foo) = %q, want foo", got)
+	}
+}
+
+func TestSubsectionGetSet(t *testing.T) {
+	ini := New()
+	ini.SubsectionSet("remote", "origin", "url", "git@example.com:foo.git")
+
+	v, ok := ini.SubsectionGet("remote", "origin", "url")
+	if !ok || v != "git@example.com:foo.git" {
+		t.Fatalf("SubsectionGet = %q, %v, want git@example.com:foo.git, true", v, ok)
+	}
+
+	if _, ok := ini.SubsectionGet("remote", "upstream", "url"); ok {
+		t.Fatal("SubsectionGet found a value in a subsection that was never set")
+	}
+}
+
+func TestSubsectionsListsAndSortsNames(t *testing.T) {
+	ini := New()
+	ini.SubsectionSet("remote", "origin", "url", "a")
+	ini.SubsectionSet("remote", "zzz", "url", "b")
+	ini.SubsectionSet("remote", "alpha", "url", "c")
+	ini.SectionSet("remote", "bare", "true") // not a subsection, shouldn't show up
+
+	got := ini.Subsections("remote")
+	want := []string{"alpha", "origin", "zzz"}
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Subsections(remote) = %v, want %v", got, want)
+	}
+}
+
+func TestSplitSubsectionKey(t *testing.T) {
+	section, sub, ok := splitSubsectionKey(subsectionKey("remote", "origin"))
+	if !ok || section != "remote" || sub != "origin" {
+		t.Fatalf("splitSubsectionKey = (%q, %q, %v), want (remote, origin, true)", section, sub, ok)
+	}
+
+	if _, _, ok := splitSubsectionKey("plainsection"); ok {
+		t.Fatal("splitSubsectionKey reported ok=true for a key with no subsection separator")
+	}
+}
+
+func TestParseAndWriteSubsectionHeaderEndToEnd(t *testing.T) {
+	ini := New()
+	ini.SetParseSection(true)
+	data := "[remote \"origin\"]\nurl=a\n[remote \"upstream\"]\nurl=b\n"
+	if err := ini.Parse([]byte(data), "\n", "="); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if v, ok := ini.SubsectionGet("remote", "origin", "url"); !ok || v != "a" {
+		t.Fatalf("remote.origin.url = %q, %v, want a, true", v, ok)
+	}
+	if v, ok := ini.SubsectionGet("remote", "upstream", "url"); !ok || v != "b" {
+		t.Fatalf("remote.upstream.url = %q, %v, want b, true", v, ok)
+	}
+}